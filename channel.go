@@ -0,0 +1,411 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Factory is a function to create new connections.
+type Factory func() (net.Conn, error)
+
+// ChannelPool implements the Pool interface based on buffered channels.
+type ChannelPool struct {
+	mu       sync.RWMutex
+	conns    chan net.Conn
+	draining bool
+
+	// net.Conn generator
+	factory Factory
+
+	// sem bounds the number of live connections (idle + checked out) at
+	// maxCap: acquiring a slot is a send, releasing one is a receive, the
+	// standard Go semaphore-over-a-buffered-channel pattern. It starts out
+	// holding one token per connection created to fill the pool. A slot is
+	// only ever acquired by a dial in GetContext, and only ever released for
+	// a connection that holds one (tracked via idleConn.slotted/PoolConn.slotted)
+	// so that Put-ing a connection the pool never dialed itself can't free
+	// someone else's slot.
+	sem chan struct{}
+
+	// idleSlotted is the number of connections currently idle in conns that
+	// hold a sem slot, i.e. len(conns) restricted to the slotted ones. It lets
+	// outstanding() compute the true number of slotted connections checked out
+	// without conflating them with idle or Put-supplied unslotted connections.
+	idleSlotted atomic.Int64
+
+	// healthCheck, if set, is run against an idle connection before it is
+	// handed out; maxIdleTime, if non-zero, additionally evicts connections
+	// that have sat idle for too long. Both are enforced on Get/GetContext
+	// and, for connections nobody ever asks for again, by reapLoop when it
+	// runs (see Config.reapInterval).
+	healthCheck func(net.Conn) error
+	maxIdleTime time.Duration
+
+	// liveMu guards live, the set of raw connections currently checked out
+	// via Get/GetContext and not yet back in the pool via Put. It lets
+	// CloseWithTimeout force-close whatever a caller never returns once its
+	// deadline elapses, instead of leaking it for the life of the process.
+	liveMu sync.Mutex
+	live   map[net.Conn]struct{}
+
+	// stats and observer back Stats() and the optional Observer callback.
+	stats    poolStats
+	observer Observer
+}
+
+// NewChannelPool returns a new pool based on buffered channels with an
+// initial capacity and maximum capacity. Factory is used when initial
+// capacity is greater than zero to fill the pool. A zero initialCap doesn't
+// fill the pool until a new Get() is called. During a Get(), if there is no
+// new connection available in the pool, a new connection will be created via
+// the Factory() method as long as fewer than maxCap connections are live;
+// otherwise Get() blocks until one is returned or destroyed.
+func NewChannelPool(initialCap, maxCap int, factory Factory) (Pool, error) {
+	return NewChannelPoolConfig(Config{
+		InitialCap: initialCap,
+		MaxCap:     maxCap,
+		Factory:    factory,
+	})
+}
+
+// NewChannelPoolConfig is like NewChannelPool, but additionally accepts a
+// HealthCheck and MaxIdleTime via cfg to guard against handing out dead or
+// stale connections. See Config for details.
+func NewChannelPoolConfig(cfg Config) (Pool, error) {
+	if cfg.InitialCap < 0 || cfg.MaxCap <= 0 || cfg.InitialCap > cfg.MaxCap {
+		return nil, errors.New("invalid capacity settings")
+	}
+
+	c := &ChannelPool{
+		conns:       make(chan net.Conn, cfg.MaxCap),
+		factory:     cfg.Factory,
+		sem:         make(chan struct{}, cfg.MaxCap),
+		healthCheck: cfg.HealthCheck,
+		maxIdleTime: cfg.MaxIdleTime,
+		observer:    cfg.Observer,
+		live:        make(map[net.Conn]struct{}),
+	}
+
+	// create initial connections, if something goes wrong,
+	// just close the pool error out.
+	for i := 0; i < cfg.InitialCap; i++ {
+		conn, err := cfg.Factory()
+		c.recordDial(err)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("factory is not able to fill the pool: %s", err)
+		}
+		c.sem <- struct{}{}
+		c.idleSlotted.Add(1)
+		c.conns <- &idleConn{Conn: conn, lastUsed: time.Now(), slotted: true}
+	}
+
+	if interval := cfg.reapInterval(); interval > 0 {
+		go c.reapLoop(interval)
+	}
+
+	return c, nil
+}
+
+func (c *ChannelPool) getConns() chan net.Conn {
+	c.mu.RLock()
+	conns := c.conns
+	c.mu.RUnlock()
+	return conns
+}
+
+// Get implements the Pool interface's Get() method; it is equivalent to
+// GetContext(context.Background()).
+func (c *ChannelPool) Get() (net.Conn, error) {
+	return c.GetContext(context.Background())
+}
+
+// GetContext implements the Pool interface's GetContext() method. It
+// returns an idle connection if one is available and passes MaxIdleTime/
+// HealthCheck, discarding and retrying any that doesn't. Otherwise, if the
+// number of live connections is still under maxCap, it dials a new one; if
+// maxCap has been reached, it blocks until a connection is returned to the
+// pool, one is destroyed, or ctx is done.
+func (c *ChannelPool) GetContext(ctx context.Context) (net.Conn, error) {
+	start := time.Now()
+
+	for {
+		c.mu.RLock()
+		conns, draining := c.conns, c.draining
+		c.mu.RUnlock()
+
+		if conns == nil || draining {
+			return nil, errors.New("pool is closed")
+		}
+
+		select {
+		case item, ok := <-conns:
+			if !ok || item == nil {
+				return nil, errors.New("pool is closed")
+			}
+			c.leavingIdle(item)
+			ic, ok := c.checkIdle(item)
+			if !ok {
+				continue
+			}
+			c.recordGet(true, 0)
+			return c.wrapConn(ic.Conn, ic.slotted), nil
+		default:
+		}
+
+		select {
+		case item, ok := <-conns:
+			if !ok || item == nil {
+				return nil, errors.New("pool is closed")
+			}
+			c.leavingIdle(item)
+			ic, ok := c.checkIdle(item)
+			if !ok {
+				continue
+			}
+			c.recordGet(true, time.Since(start))
+			return c.wrapConn(ic.Conn, ic.slotted), nil
+		case c.sem <- struct{}{}:
+			conn, err := c.factory()
+			c.recordDial(err)
+			if err != nil {
+				<-c.sem
+				c.recordGet(false, time.Since(start))
+				return nil, err
+			}
+			c.recordGet(false, time.Since(start))
+			return c.wrapConn(conn, true), nil
+		case <-ctx.Done():
+			c.recordGet(false, time.Since(start))
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// leavingIdle records that item, just popped from conns, is no longer idle,
+// decrementing idleSlotted if it held a sem slot. Callers that put it back
+// (Put, reapOnce) are responsible for incrementing idleSlotted again.
+func (c *ChannelPool) leavingIdle(item net.Conn) {
+	if ic, ok := item.(*idleConn); ok && ic.slotted {
+		c.idleSlotted.Add(-1)
+	}
+}
+
+// checkIdle validates an idle connection popped from the pool against
+// MaxIdleTime and HealthCheck. If the connection fails either check, it is
+// closed, its semaphore slot is released (if it held one), and ok is false.
+func (c *ChannelPool) checkIdle(item net.Conn) (ic *idleConn, ok bool) {
+	ic, wrapped := item.(*idleConn)
+	if !wrapped {
+		ic = &idleConn{Conn: item, lastUsed: time.Now()}
+	}
+
+	if c.maxIdleTime > 0 && time.Since(ic.lastUsed) > c.maxIdleTime {
+		if ic.slotted {
+			c.releaseSlot()
+		}
+		ic.Conn.Close()
+		c.stats.discards.Add(1)
+		c.recordClose()
+		return nil, false
+	}
+
+	if c.healthCheck != nil {
+		if err := c.healthCheck(ic.Conn); err != nil {
+			if ic.slotted {
+				c.releaseSlot()
+			}
+			ic.Conn.Close()
+			c.stats.discards.Add(1)
+			c.recordClose()
+			return nil, false
+		}
+	}
+
+	return ic, true
+}
+
+// Put implements the Pool interface's Put() method. If the pool is full,
+// conn is closed and an error is returned. If the pool is closed, conn is
+// closed and the error from closing it, if any, is returned.
+func (c *ChannelPool) Put(conn net.Conn) error {
+	if conn == nil {
+		return errors.New("connection is nil. rejecting")
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// slotted tracks whether conn holds a sem slot, i.e. it was originally
+	// obtained through Get/GetContext rather than dialed independently and
+	// handed to Put directly. Only a slot conn actually holds may be
+	// released below; otherwise Put-ing externally-dialed connections would
+	// free slots belonging to other, still checked-out connections.
+	slotted := false
+
+	// never store a *PoolConn in the channel, only the raw connection it wraps
+	if pc, ok := conn.(*PoolConn); ok {
+		slotted = pc.slotted
+		conn = pc.Conn
+	}
+	c.untrackLive(conn)
+
+	if c.conns == nil {
+		// pool is closed, close passed connection
+		if slotted {
+			c.releaseSlot()
+		}
+		err := conn.Close()
+		c.recordClose()
+		c.recordPut(false)
+		return err
+	}
+
+	select {
+	case c.conns <- &idleConn{Conn: conn, lastUsed: time.Now(), slotted: slotted}:
+		if slotted {
+			c.idleSlotted.Add(1)
+		}
+		c.recordPut(true)
+		return nil
+	default:
+		// pool is full, close passed connection
+		if slotted {
+			c.releaseSlot()
+		}
+		if err := conn.Close(); err != nil {
+			c.recordClose()
+			c.recordPut(false)
+			return err
+		}
+		c.recordClose()
+		c.recordPut(false)
+		return errors.New("pool is full, closing passed connection")
+	}
+}
+
+// releaseSlot gives back a semaphore slot for a connection that is being
+// permanently destroyed instead of returned to the pool. It is a no-op
+// rather than a block when no slot is outstanding, e.g. the connection was
+// never obtained through Get/GetContext.
+func (c *ChannelPool) releaseSlot() {
+	select {
+	case <-c.sem:
+	default:
+	}
+}
+
+// Close closes the pool and all its connections. After Close() the pool is
+// no longer usable.
+func (c *ChannelPool) Close() {
+	c.mu.Lock()
+	conns := c.conns
+	c.conns = nil
+	c.factory = nil
+	c.draining = true
+	c.mu.Unlock()
+
+	if conns == nil {
+		return
+	}
+
+	close(conns)
+	for conn := range conns {
+		conn.Close()
+		c.recordClose()
+	}
+}
+
+// CloseWithTimeout implements the Pool interface's CloseWithTimeout()
+// method. It stops accepting new Gets immediately, then waits up to d for
+// connections currently checked out to be returned via Put before force
+// closing the pool and anything still outstanding.
+func (c *ChannelPool) CloseWithTimeout(d time.Duration) {
+	c.mu.Lock()
+	if c.conns == nil || c.draining {
+		c.mu.Unlock()
+		return
+	}
+	c.draining = true
+	c.mu.Unlock()
+
+	deadline := time.Now().Add(d)
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+
+	for c.outstanding() > 0 && time.Now().Before(deadline) {
+		<-ticker.C
+	}
+
+	c.forceCloseLive()
+	c.Close()
+}
+
+// outstanding returns the number of slotted connections currently checked
+// out of the pool, i.e. holding a sem token but not sitting idle in conns.
+// Connections Put into the pool that never held a slot are never counted,
+// so they can't be mistaken for checked-out capacity.
+func (c *ChannelPool) outstanding() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.conns == nil {
+		return 0
+	}
+	n := len(c.sem) - int(c.idleSlotted.Load())
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Len returns the current number of idle connections in the pool.
+func (c *ChannelPool) Len() int { return len(c.getConns()) }
+
+// Cap returns the maximum capacity of the pool.
+func (c *ChannelPool) Cap() int { return cap(c.getConns()) }
+
+// wrapConn wraps conn in a *PoolConn so that its Close() returns it to the
+// pool. slotted must be true iff conn currently holds a sem slot, so Put can
+// later release that slot only if it's actually outstanding.
+func (c *ChannelPool) wrapConn(conn net.Conn, slotted bool) net.Conn {
+	c.trackLive(conn)
+	p := &PoolConn{c: c, slotted: slotted}
+	p.Conn = conn
+	return p
+}
+
+// trackLive records conn as checked out of the pool, so CloseWithTimeout can
+// force-close it if it's never returned via Put.
+func (c *ChannelPool) trackLive(conn net.Conn) {
+	c.liveMu.Lock()
+	c.live[conn] = struct{}{}
+	c.liveMu.Unlock()
+}
+
+// untrackLive reverses trackLive once conn is back in the pool's hands,
+// whether returned via Put or destroyed via MarkUnusable.
+func (c *ChannelPool) untrackLive(conn net.Conn) {
+	c.liveMu.Lock()
+	delete(c.live, conn)
+	c.liveMu.Unlock()
+}
+
+// forceCloseLive closes and untracks every connection still checked out,
+// for CloseWithTimeout to call once its deadline has elapsed so a caller
+// that never calls Put back doesn't leak the connection indefinitely.
+func (c *ChannelPool) forceCloseLive() {
+	c.liveMu.Lock()
+	live := c.live
+	c.live = make(map[net.Conn]struct{})
+	c.liveMu.Unlock()
+
+	for conn := range live {
+		conn.Close()
+		c.recordClose()
+	}
+}
@@ -0,0 +1,156 @@
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChannelPool_GetContext_HardCap(t *testing.T) {
+	p, _ := NewChannelPool(1, 1, factory)
+	defer p.Close()
+
+	conn, err := p.GetContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetContext error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = p.GetContext(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("GetContext error. Expecting %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	if err := p.Put(conn); err != nil {
+		t.Errorf("Put error: %s", err)
+	}
+
+	if _, err := p.GetContext(context.Background()); err != nil {
+		t.Errorf("GetContext error after Put freed a slot: %s", err)
+	}
+}
+
+// TestChannelPool_MarkUnusable_ReleasesSlot guards against a permanent
+// capacity leak: destroying a connection via MarkUnusable()+Close() must
+// release its sem slot just like a discard via checkIdle/Put does, or every
+// broken connection a long-running pool destroys shrinks maxCap for good.
+func TestChannelPool_MarkUnusable_ReleasesSlot(t *testing.T) {
+	p, _ := NewChannelPool(1, 1, factory)
+	defer p.Close()
+
+	conn, err := p.GetContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetContext error: %s", err)
+	}
+
+	conn.(*PoolConn).MarkUnusable()
+	if err := conn.Close(); err != nil {
+		t.Errorf("Close error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := p.GetContext(ctx); err != nil {
+		t.Errorf("GetContext error after MarkUnusable+Close freed a slot: %s", err)
+	}
+}
+
+// TestChannelPool_Put_DiscardingExternalConnDoesNotFreeSlot guards against a
+// hard-cap bypass: discarding an externally-dialed connection (one Put
+// directly instead of obtained via Get/GetContext) must not release a sem
+// slot, since it never held one. Otherwise it can free a slot belonging to a
+// connection that is still legitimately checked out, letting a later Get
+// dial past maxCap while that connection is still in use.
+func TestChannelPool_Put_DiscardingExternalConnDoesNotFreeSlot(t *testing.T) {
+	p, _ := NewChannelPool(1, 1, factory)
+	defer p.Close()
+
+	conn, err := p.GetContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetContext error: %s", err)
+	}
+
+	// conn is now checked out and conns is empty, so the first external Put
+	// below goes idle; the second finds the pool full and is discarded.
+	extra1, err := factory()
+	if err != nil {
+		t.Fatalf("factory error: %s", err)
+	}
+	extra2, err := factory()
+	if err != nil {
+		t.Fatalf("factory error: %s", err)
+	}
+	if err := p.Put(extra1); err != nil {
+		t.Fatalf("Put error: %s", err)
+	}
+	if err := p.Put(extra2); err == nil {
+		t.Errorf("Put error. Expecting an error, the pool is full")
+	}
+
+	// draining extra1 must not free any sem slot; conn is still checked out.
+	if _, err := p.GetContext(context.Background()); err != nil {
+		t.Fatalf("GetContext error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := p.GetContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("GetContext error. Expecting %v, got %v (Get must block instead of dialing past maxCap while conn is still checked out)", context.DeadlineExceeded, err)
+	}
+
+	p.Put(conn)
+}
+
+func TestChannelPool_CloseWithTimeout(t *testing.T) {
+	p, _ := NewChannelPool(1, 1, factory)
+
+	conn, err := p.GetContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetContext error: %s", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.CloseWithTimeout(200 * time.Millisecond)
+		close(done)
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if err := p.Put(conn); err != nil {
+		t.Errorf("Put error: %s", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("CloseWithTimeout did not return after the connection was put back")
+	}
+
+	if _, err := p.GetContext(context.Background()); err == nil {
+		t.Errorf("GetContext error. Expecting an error once the pool is closed")
+	}
+}
+
+// TestChannelPool_CloseWithTimeout_ForceClosesAbandonedConn guards against a
+// leak: a connection Get'd but never Put back must still be force-closed
+// once CloseWithTimeout's deadline elapses, since there's nothing else that
+// will ever close it.
+func TestChannelPool_CloseWithTimeout_ForceClosesAbandonedConn(t *testing.T) {
+	p, _ := NewChannelPool(1, 1, factory)
+
+	conn, err := p.GetContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetContext error: %s", err)
+	}
+
+	// abandon conn: never Put it back
+	p.CloseWithTimeout(30 * time.Millisecond)
+
+	buf := make([]byte, 1)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(buf); err == nil {
+		t.Errorf("Read error. Expecting an error, the abandoned conn should have been force-closed")
+	}
+}
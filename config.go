@@ -0,0 +1,62 @@
+package pool
+
+import (
+	"net"
+	"time"
+)
+
+// Config groups the parameters for constructing a ChannelPool, including
+// optional liveness checking of idle connections.
+type Config struct {
+	// InitialCap is the number of connections created up front to fill the
+	// pool.
+	InitialCap int
+
+	// MaxCap is the maximum number of live connections (idle + checked
+	// out) the pool will hold.
+	MaxCap int
+
+	// Factory creates a new connection.
+	Factory Factory
+
+	// Observer, if set, is notified of Get/Put/dial activity so it can be
+	// exported to an external metrics system. See Stats() for a polling
+	// alternative that doesn't require plugging in a callback.
+	Observer Observer
+
+	// HealthCheck, if set, is run against an idle connection before it is
+	// handed out by Get/GetContext. A connection that fails the check is
+	// discarded and another is fetched or dialed in its place. TCP
+	// keepalives alone won't catch an application-layer half-close, so a
+	// HealthCheck that does a cheap read/write is the only way to avoid
+	// handing out a dead socket.
+	HealthCheck func(net.Conn) error
+
+	// MaxIdleTime, if greater than zero, is the maximum duration a
+	// connection may sit idle in the pool before it is closed instead of
+	// being handed out or kept around by the reaper. Zero disables idle
+	// expiry.
+	MaxIdleTime time.Duration
+
+	// IdleCheckInterval is how often the background reaper walks idle
+	// connections to apply HealthCheck and MaxIdleTime. Defaults to
+	// MaxIdleTime/2 when MaxIdleTime is set; the reaper is disabled
+	// entirely when both IdleCheckInterval and MaxIdleTime are zero, even
+	// if a HealthCheck is configured.
+	IdleCheckInterval time.Duration
+}
+
+// reapInterval returns how often the background reaper should run, or zero
+// if it should not run at all. The reaper only runs when it has something to
+// enforce in the background: an explicit IdleCheckInterval, or MaxIdleTime
+// (which defaults the interval to MaxIdleTime/2). A HealthCheck with neither
+// set is still applied lazily on Get/GetContext, just not proactively.
+func (cfg Config) reapInterval() time.Duration {
+	if cfg.IdleCheckInterval > 0 {
+		return cfg.IdleCheckInterval
+	}
+	if cfg.MaxIdleTime > 0 {
+		return cfg.MaxIdleTime / 2
+	}
+	return 0
+}
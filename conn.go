@@ -0,0 +1,56 @@
+package pool
+
+import (
+	"net"
+	"sync"
+)
+
+// PoolConn is a wrapper around net.Conn to modify the behavior of the
+// connection's Close() method so that it puts the connection back into the
+// pool it came from, instead of actually closing it. This lets callers use
+// the connection idiomatically with a single `defer conn.Close()`.
+type PoolConn struct {
+	net.Conn
+	mu       sync.RWMutex
+	c        *ChannelPool
+	unusable bool
+
+	// slotted reports whether this connection was created by the pool's
+	// own Factory and therefore holds a capacity slot in c.sem. It lets
+	// Put release that slot only for connections that actually acquired
+	// one, instead of for whatever net.Conn happens to be passed to it.
+	slotted bool
+}
+
+// Close puts the given connection back into the pool instead of closing it,
+// unless the connection has been marked unusable via MarkUnusable(), in
+// which case it is destroyed for good and its sem slot, if any, is released
+// back to the pool so capacity doesn't shrink permanently.
+func (p *PoolConn) Close() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.unusable {
+		if p.slotted {
+			p.c.releaseSlot()
+		}
+		if p.Conn != nil {
+			p.c.untrackLive(p.Conn)
+			err := p.Conn.Close()
+			p.c.recordClose()
+			return err
+		}
+		return nil
+	}
+	return p.c.Put(p)
+}
+
+// MarkUnusable marks the connection as no longer usable, so that the next
+// call to Close() destroys it instead of returning it to the pool. Use this
+// whenever a connection is known to be broken, e.g. after a read/write error,
+// so a dead socket doesn't get recycled to another caller.
+func (p *PoolConn) MarkUnusable() {
+	p.mu.Lock()
+	p.unusable = true
+	p.mu.Unlock()
+}
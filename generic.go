@@ -0,0 +1,361 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// TypedPool is the generic counterpart to Pool: it holds any resource type
+// T - LDAP clients, gRPC streams, Redis connections, SSH sessions - instead
+// of being limited to net.Conn. It mirrors the net.Conn pool's core Get/
+// Put/Close/Len/Cap/Stats surface and the same hard-cap enforcement; T isn't
+// constrained to io.Closer so that types without a method literally named
+// Close() can still be pooled, using the close function supplied to
+// NewTyped to destroy an item instead. Unlike PoolConn, there is no
+// auto-return-on-close wrapper: T has no common method a wrapper could
+// override to hook Close(), so callers must Put items back explicitly.
+type TypedPool[T any] interface {
+	// Get returns an item from the pool, creating a new one via Factory if
+	// none are idle. If the pool is already at MaxCap, Get blocks until an
+	// item is returned via Put or destroyed.
+	Get() (T, error)
+
+	// Put returns item to the pool. If the pool is full or closed, item is
+	// destroyed via the close function passed to NewTyped instead.
+	Put(item T) error
+
+	// Close closes the pool and destroys every item still in it.
+	Close()
+
+	// Len returns the current number of idle items in the pool.
+	Len() int
+
+	// Cap returns the maximum capacity of the pool.
+	Cap() int
+
+	// Stats returns a snapshot of the pool's lifetime counters. Closes,
+	// WaitCount and WaitDuration are not tracked and are always zero.
+	Stats() Stats
+}
+
+// ConnPool is TypedPool specialized to net.Conn, kept as a thin alias so
+// code built around the generic pool can share a name with this package's
+// original net.Conn-specific Pool when that's all it needs.
+type ConnPool = TypedPool[net.Conn]
+
+// TypedConfig groups the parameters for constructing a generic pool via
+// NewTypedConfig, mirroring Config's role for NewChannelPoolConfig.
+type TypedConfig[T comparable] struct {
+	// InitialCap is the number of items created up front to fill the pool.
+	InitialCap int
+
+	// MaxCap is the maximum number of live items (idle + checked out) the
+	// pool will hold.
+	MaxCap int
+
+	// Factory creates a new item.
+	Factory func() (T, error)
+
+	// CloseFn destroys an item whenever it can't be returned to the pool
+	// (pool full or closed).
+	CloseFn func(T) error
+
+	// Observer, if set, is notified of Get/Put/dial activity so it can be
+	// exported to an external metrics system. OnGet is always passed a zero
+	// waited duration.
+	Observer Observer
+}
+
+// typedChannelPool implements TypedPool based on buffered channels, the
+// same hard-cap design as ChannelPool generalized over T. Get/Put work with
+// raw T, so there's no wrapper to stash a per-item slotted flag on the way
+// PoolConn does; T is constrained to comparable instead, so slotted can
+// track item identity directly. An item is added to slotted exactly once,
+// when factory first creates it, and removed exactly once, when it is
+// permanently destroyed via closeFn - it lets Put release a sem slot only
+// for an item that actually holds one, instead of for whatever T happens to
+// be passed to it. idleSlotted separately counts how many slotted items are
+// currently idle in items, so outstanding() can compute the true checked-out
+// count without draining the channel to inspect it.
+type typedChannelPool[T comparable] struct {
+	mu    sync.RWMutex
+	items chan T
+
+	sem         chan struct{}
+	idleSlotted atomic.Int64
+
+	slottedMu sync.Mutex
+	slotted   map[T]struct{}
+
+	factory func() (T, error)
+	closeFn func(T) error
+
+	stats    poolStats
+	observer Observer
+}
+
+// NewTyped returns a new generic pool with an initial capacity and maximum
+// capacity. factory is used when initialCap is greater than zero to fill
+// the pool, and closeFn is called to destroy an item whenever it can't be
+// returned to the pool (pool full or closed).
+func NewTyped[T comparable](initialCap, maxCap int, factory func() (T, error), closeFn func(T) error) (TypedPool[T], error) {
+	return NewTypedConfig(TypedConfig[T]{
+		InitialCap: initialCap,
+		MaxCap:     maxCap,
+		Factory:    factory,
+		CloseFn:    closeFn,
+	})
+}
+
+// NewTypedConfig is like NewTyped, but additionally accepts an Observer via
+// cfg. See TypedConfig for details.
+func NewTypedConfig[T comparable](cfg TypedConfig[T]) (TypedPool[T], error) {
+	if cfg.InitialCap < 0 || cfg.MaxCap <= 0 || cfg.InitialCap > cfg.MaxCap {
+		return nil, errors.New("invalid capacity settings")
+	}
+
+	c := &typedChannelPool[T]{
+		items:    make(chan T, cfg.MaxCap),
+		sem:      make(chan struct{}, cfg.MaxCap),
+		slotted:  make(map[T]struct{}),
+		factory:  cfg.Factory,
+		closeFn:  cfg.CloseFn,
+		observer: cfg.Observer,
+	}
+
+	// create initial items, if something goes wrong,
+	// just close the pool and error out.
+	for i := 0; i < cfg.InitialCap; i++ {
+		item, err := cfg.Factory()
+		c.recordDial(err)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("factory is not able to fill the pool: %s", err)
+		}
+		c.sem <- struct{}{}
+		c.addSlotted(item)
+		c.idleSlotted.Add(1)
+		c.items <- item
+	}
+
+	return c, nil
+}
+
+func (c *typedChannelPool[T]) getItems() chan T {
+	c.mu.RLock()
+	items := c.items
+	c.mu.RUnlock()
+	return items
+}
+
+// addSlotted records item as holding a sem slot for its whole lifetime,
+// called exactly once, right after factory creates it.
+func (c *typedChannelPool[T]) addSlotted(item T) {
+	c.slottedMu.Lock()
+	c.slotted[item] = struct{}{}
+	c.slottedMu.Unlock()
+}
+
+// removeSlotted reverses addSlotted, called exactly once, right before item
+// is permanently destroyed via closeFn.
+func (c *typedChannelPool[T]) removeSlotted(item T) {
+	c.slottedMu.Lock()
+	delete(c.slotted, item)
+	c.slottedMu.Unlock()
+}
+
+// isSlotted reports whether item currently holds a sem slot.
+func (c *typedChannelPool[T]) isSlotted(item T) bool {
+	c.slottedMu.Lock()
+	_, ok := c.slotted[item]
+	c.slottedMu.Unlock()
+	return ok
+}
+
+func (c *typedChannelPool[T]) releaseSlot() {
+	select {
+	case <-c.sem:
+	default:
+	}
+}
+
+// Get implements the TypedPool interface's Get() method. If there is no
+// idle item available and the pool is already at MaxCap, Get blocks until
+// an item is returned via Put or destroyed.
+func (c *typedChannelPool[T]) Get() (T, error) {
+	items := c.getItems()
+	if items == nil {
+		var zero T
+		return zero, errors.New("pool is closed")
+	}
+
+	select {
+	case item := <-items:
+		c.leavingIdle(item)
+		c.recordGet(true)
+		return item, nil
+	default:
+	}
+
+	select {
+	case item := <-items:
+		c.leavingIdle(item)
+		c.recordGet(true)
+		return item, nil
+	case c.sem <- struct{}{}:
+		item, err := c.factory()
+		c.recordDial(err)
+		if err != nil {
+			<-c.sem
+			c.recordGet(false)
+			var zero T
+			return zero, err
+		}
+		c.addSlotted(item)
+		c.recordGet(false)
+		return item, nil
+	}
+}
+
+// leavingIdle records that item, just popped from items, is no longer idle,
+// decrementing idleSlotted if it holds a sem slot.
+func (c *typedChannelPool[T]) leavingIdle(item T) {
+	if c.isSlotted(item) {
+		c.idleSlotted.Add(-1)
+	}
+}
+
+// Put implements the TypedPool interface's Put() method. If the pool is
+// full, item is destroyed via closeFn and an error is returned. If the pool
+// is closed, item is destroyed via closeFn and closeFn's error is returned.
+func (c *typedChannelPool[T]) Put(item T) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// slotted tracks whether item holds a sem slot, i.e. it was originally
+	// created by factory rather than constructed independently and handed
+	// to Put directly. Only a slot item actually holds may be released
+	// below; otherwise Put-ing externally-created items would free slots
+	// belonging to other, still checked-out items.
+	slotted := c.isSlotted(item)
+
+	if c.items == nil {
+		if slotted {
+			c.releaseSlot()
+			c.removeSlotted(item)
+		}
+		err := c.closeFn(item)
+		c.recordPut(false)
+		return err
+	}
+
+	select {
+	case c.items <- item:
+		if slotted {
+			c.idleSlotted.Add(1)
+		}
+		c.recordPut(true)
+		return nil
+	default:
+		if slotted {
+			c.releaseSlot()
+			c.removeSlotted(item)
+		}
+		c.recordPut(false)
+		if err := c.closeFn(item); err != nil {
+			return err
+		}
+		return errors.New("pool is full, closing passed item")
+	}
+}
+
+// Close closes the pool and destroys every item still in it. After Close()
+// the pool is no longer usable.
+func (c *typedChannelPool[T]) Close() {
+	c.mu.Lock()
+	items := c.items
+	c.items = nil
+	c.mu.Unlock()
+
+	if items == nil {
+		return
+	}
+
+	close(items)
+	for item := range items {
+		c.removeSlotted(item)
+		c.closeFn(item)
+	}
+}
+
+// Len returns the current number of idle items in the pool.
+func (c *typedChannelPool[T]) Len() int { return len(c.getItems()) }
+
+// Cap returns the maximum capacity of the pool.
+func (c *typedChannelPool[T]) Cap() int { return cap(c.getItems()) }
+
+// outstanding returns the number of slotted items currently checked out of
+// the pool, i.e. holding a sem token but not sitting idle in items.
+func (c *typedChannelPool[T]) outstanding() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.items == nil {
+		return 0
+	}
+	n := len(c.sem) - int(c.idleSlotted.Load())
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+func (c *typedChannelPool[T]) recordDial(err error) {
+	if err != nil {
+		c.stats.dialErrors.Add(1)
+	} else {
+		c.stats.dials.Add(1)
+	}
+	if c.observer != nil {
+		c.observer.OnDial(err)
+	}
+}
+
+func (c *typedChannelPool[T]) recordGet(hit bool) {
+	c.stats.gets.Add(1)
+	if hit {
+		c.stats.hits.Add(1)
+	} else {
+		c.stats.misses.Add(1)
+	}
+	if c.observer != nil {
+		c.observer.OnGet(hit, 0)
+	}
+}
+
+func (c *typedChannelPool[T]) recordPut(accepted bool) {
+	c.stats.puts.Add(1)
+	if !accepted {
+		c.stats.discards.Add(1)
+	}
+	if c.observer != nil {
+		c.observer.OnPut(accepted)
+	}
+}
+
+// Stats returns a snapshot of the pool's lifetime counters.
+func (c *typedChannelPool[T]) Stats() Stats {
+	return Stats{
+		Dials:        c.stats.dials.Load(),
+		DialErrors:   c.stats.dialErrors.Load(),
+		Gets:         c.stats.gets.Load(),
+		Hits:         c.stats.hits.Load(),
+		Misses:       c.stats.misses.Load(),
+		Puts:         c.stats.puts.Load(),
+		Discards:     c.stats.discards.Load(),
+		CurrentIdle:  c.Len(),
+		CurrentInUse: c.outstanding(),
+	}
+}
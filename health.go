@@ -0,0 +1,75 @@
+package pool
+
+import (
+	"net"
+	"time"
+)
+
+// idleConn wraps a net.Conn sitting idle in the pool's channel to record
+// when it was last returned, so Get/GetContext and the reaper can evict
+// connections that have been idle for longer than MaxIdleTime. slotted
+// mirrors PoolConn.slotted: it's true only for connections that were
+// created by the pool's own Factory and therefore hold a c.sem slot.
+type idleConn struct {
+	net.Conn
+	lastUsed time.Time
+	slotted  bool
+}
+
+// reapLoop periodically walks the pool's idle connections, applying
+// HealthCheck and MaxIdleTime to each and closing any that fail. It exits
+// once the pool is closed.
+func (c *ChannelPool) reapLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !c.reapOnce() {
+			return
+		}
+	}
+}
+
+// reapOnce walks every connection currently idle in the pool once, closing
+// any that are stale or fail HealthCheck and putting the rest back
+// unchanged. It reports whether the pool is still open.
+func (c *ChannelPool) reapOnce() bool {
+	conns := c.getConns()
+	if conns == nil {
+		return false
+	}
+
+	// snapshot the current idle count: conns Put back in by callers during
+	// this pass are left alone, only what was already idle is examined.
+	for n := len(conns); n > 0; n-- {
+		var item net.Conn
+		select {
+		case item = <-conns:
+		default:
+			return true
+		}
+
+		c.leavingIdle(item)
+		ic, ok := c.checkIdle(item)
+		if !ok {
+			continue
+		}
+
+		select {
+		case conns <- ic:
+			if ic.slotted {
+				c.idleSlotted.Add(1)
+			}
+		default:
+			// pool shrank or filled up concurrently, don't leak the conn
+			if ic.slotted {
+				c.releaseSlot()
+			}
+			ic.Conn.Close()
+			c.stats.discards.Add(1)
+			c.recordClose()
+		}
+	}
+
+	return true
+}
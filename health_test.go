@@ -0,0 +1,95 @@
+package pool
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestChannelPool_HealthCheck(t *testing.T) {
+	var calls int
+	healthy := true
+
+	p, err := NewChannelPoolConfig(Config{
+		InitialCap: 1,
+		MaxCap:     1,
+		Factory:    factory,
+		HealthCheck: func(net.Conn) error {
+			calls++
+			if !healthy {
+				return errors.New("connection is dead")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewChannelPoolConfig error: %s", err)
+	}
+	defer p.Close()
+
+	conn, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get error: %s", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected HealthCheck to run once, got %d", calls)
+	}
+
+	if err := p.Put(conn); err != nil {
+		t.Fatalf("Put error: %s", err)
+	}
+
+	healthy = false
+	if _, err := p.Get(); err != nil {
+		t.Errorf("Get error: %s", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected HealthCheck to run again on the next Get, got %d calls", calls)
+	}
+	if p.Len() != 0 {
+		t.Errorf("expected the unhealthy connection to be discarded, got Len() = %d", p.Len())
+	}
+}
+
+func TestChannelPool_MaxIdleTime(t *testing.T) {
+	p, err := NewChannelPoolConfig(Config{
+		InitialCap:  1,
+		MaxCap:      1,
+		Factory:     factory,
+		MaxIdleTime: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewChannelPoolConfig error: %s", err)
+	}
+	defer p.Close()
+
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get error: %s", err)
+	}
+	if err := p.Put(conn); err != nil {
+		t.Errorf("Put error: %s", err)
+	}
+}
+
+func TestChannelPool_ReaperEvictsStaleConns(t *testing.T) {
+	p, err := NewChannelPoolConfig(Config{
+		InitialCap:  1,
+		MaxCap:      1,
+		Factory:     factory,
+		MaxIdleTime: 30 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewChannelPoolConfig error: %s", err)
+	}
+	defer p.Close()
+
+	time.Sleep(200 * time.Millisecond)
+
+	if p.Len() != 0 {
+		t.Errorf("expected the reaper to evict the stale idle connection, got Len() = %d", p.Len())
+	}
+}
@@ -0,0 +1,139 @@
+package pool
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Observer lets callers plug the pool's activity into an external metrics
+// system (Prometheus, OpenTelemetry, ...) without the pool depending on any
+// particular exporter. All methods are called synchronously from whichever
+// goroutine triggered the event, so implementations should be cheap and
+// non-blocking.
+type Observer interface {
+	// OnGet is called after every Get/GetContext call, except those that
+	// return because the pool is closed. hit reports whether
+	// an idle connection was reused; waited is how long the call took to
+	// find or create a connection.
+	OnGet(hit bool, waited time.Duration)
+
+	// OnPut is called after every Put call. accepted reports whether the
+	// connection was returned to the pool or closed instead.
+	OnPut(accepted bool)
+
+	// OnDial is called after every call to the pool's Factory, whether
+	// made while filling the pool or while servicing a Get.
+	OnDial(err error)
+}
+
+// Stats is a snapshot of a pool's lifetime counters, suitable for
+// periodically exporting to Prometheus/OpenTelemetry or similar.
+type Stats struct {
+	// Dials and DialErrors count calls to Factory, split by outcome.
+	Dials      int64
+	DialErrors int64
+
+	// Gets is the total number of Get/GetContext calls, not counting those
+	// that return because the pool is closed. Hits is the subset served from
+	// an idle connection; Misses is the rest: calls that dialed a new one,
+	// whether or not the dial succeeded, or whose ctx was done first.
+	Gets   int64
+	Hits   int64
+	Misses int64
+
+	// Puts is the total number of Put calls. Discards is the subset of
+	// connections closed instead of recycled, e.g. because the pool was
+	// full or closed, or the connection failed HealthCheck/MaxIdleTime.
+	Puts     int64
+	Discards int64
+
+	// Closes is the total number of underlying connections the pool has
+	// closed, across Put, the idle reaper, and pool shutdown.
+	Closes int64
+
+	// CurrentIdle and CurrentInUse are the current number of idle and
+	// checked-out connections, respectively. CurrentInUse counts only
+	// connections the pool dialed itself; a connection handed to Put from
+	// outside is not counted while it is checked out.
+	CurrentIdle  int
+	CurrentInUse int
+
+	// WaitCount is how many Get/GetContext calls didn't find an idle
+	// connection immediately; WaitDuration is the cumulative time spent
+	// dialing or waiting for room under maxCap across those calls.
+	WaitCount    int64
+	WaitDuration time.Duration
+}
+
+// poolStats holds the atomic counters backing a ChannelPool's Stats().
+type poolStats struct {
+	dials        atomic.Int64
+	dialErrors   atomic.Int64
+	gets         atomic.Int64
+	hits         atomic.Int64
+	misses       atomic.Int64
+	puts         atomic.Int64
+	discards     atomic.Int64
+	closes       atomic.Int64
+	waitCount    atomic.Int64
+	waitDuration atomic.Int64 // nanoseconds
+}
+
+func (c *ChannelPool) recordDial(err error) {
+	if err != nil {
+		c.stats.dialErrors.Add(1)
+	} else {
+		c.stats.dials.Add(1)
+	}
+	if c.observer != nil {
+		c.observer.OnDial(err)
+	}
+}
+
+func (c *ChannelPool) recordGet(hit bool, waited time.Duration) {
+	c.stats.gets.Add(1)
+	if hit {
+		c.stats.hits.Add(1)
+	} else {
+		c.stats.misses.Add(1)
+	}
+	if waited > 0 {
+		c.stats.waitCount.Add(1)
+		c.stats.waitDuration.Add(int64(waited))
+	}
+	if c.observer != nil {
+		c.observer.OnGet(hit, waited)
+	}
+}
+
+func (c *ChannelPool) recordPut(accepted bool) {
+	c.stats.puts.Add(1)
+	if !accepted {
+		c.stats.discards.Add(1)
+	}
+	if c.observer != nil {
+		c.observer.OnPut(accepted)
+	}
+}
+
+func (c *ChannelPool) recordClose() {
+	c.stats.closes.Add(1)
+}
+
+// Stats returns a snapshot of the pool's lifetime counters.
+func (c *ChannelPool) Stats() Stats {
+	return Stats{
+		Dials:        c.stats.dials.Load(),
+		DialErrors:   c.stats.dialErrors.Load(),
+		Gets:         c.stats.gets.Load(),
+		Hits:         c.stats.hits.Load(),
+		Misses:       c.stats.misses.Load(),
+		Puts:         c.stats.puts.Load(),
+		Discards:     c.stats.discards.Load(),
+		Closes:       c.stats.closes.Load(),
+		CurrentIdle:  c.Len(),
+		CurrentInUse: c.outstanding(),
+		WaitCount:    c.stats.waitCount.Load(),
+		WaitDuration: time.Duration(c.stats.waitDuration.Load()),
+	}
+}
@@ -0,0 +1,187 @@
+package pool
+
+import (
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	gets, hits int
+	puts       int
+	putsOK     int
+	dials      int
+}
+
+func (o *recordingObserver) OnGet(hit bool, waited time.Duration) {
+	o.gets++
+	if hit {
+		o.hits++
+	}
+}
+
+func (o *recordingObserver) OnPut(accepted bool) {
+	o.puts++
+	if accepted {
+		o.putsOK++
+	}
+}
+
+func (o *recordingObserver) OnDial(err error) {
+	o.dials++
+}
+
+func TestChannelPool_Stats(t *testing.T) {
+	obs := &recordingObserver{}
+
+	p, err := NewChannelPoolConfig(Config{
+		InitialCap: 1,
+		MaxCap:     2,
+		Factory:    factory,
+		Observer:   obs,
+	})
+	if err != nil {
+		t.Fatalf("NewChannelPoolConfig error: %s", err)
+	}
+	defer p.Close()
+
+	conn, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get error: %s", err)
+	}
+
+	conn2, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get error: %s", err)
+	}
+
+	if err := p.Put(conn); err != nil {
+		t.Fatalf("Put error: %s", err)
+	}
+	if err := p.Put(conn2); err != nil {
+		t.Fatalf("Put error: %s", err)
+	}
+
+	stats := p.Stats()
+	if stats.Gets != 2 {
+		t.Errorf("expected 2 Gets, got %d", stats.Gets)
+	}
+	if stats.Hits != 1 {
+		t.Errorf("expected 1 Hit (the initial idle conn), got %d", stats.Hits)
+	}
+	if stats.Misses != 1 {
+		t.Errorf("expected 1 Miss (the dialed conn), got %d", stats.Misses)
+	}
+	if stats.Dials != 2 {
+		t.Errorf("expected 2 Dials (initial fill + the miss), got %d", stats.Dials)
+	}
+	if stats.Puts != 2 {
+		t.Errorf("expected 2 Puts, got %d", stats.Puts)
+	}
+	if stats.CurrentIdle != 2 {
+		t.Errorf("expected CurrentIdle == 2, got %d", stats.CurrentIdle)
+	}
+	if stats.CurrentInUse != 0 {
+		t.Errorf("expected CurrentInUse == 0, got %d", stats.CurrentInUse)
+	}
+
+	if obs.gets != 2 || obs.hits != 1 {
+		t.Errorf("observer OnGet mismatch: gets=%d hits=%d", obs.gets, obs.hits)
+	}
+	if obs.puts != 2 || obs.putsOK != 2 {
+		t.Errorf("observer OnPut mismatch: puts=%d ok=%d", obs.puts, obs.putsOK)
+	}
+	if obs.dials != 2 {
+		t.Errorf("observer OnDial mismatch: dials=%d", obs.dials)
+	}
+}
+
+func TestChannelPool_StatsDiscards(t *testing.T) {
+	p, err := NewChannelPoolConfig(Config{
+		InitialCap: 1,
+		MaxCap:     1,
+		Factory:    factory,
+	})
+	if err != nil {
+		t.Fatalf("NewChannelPoolConfig error: %s", err)
+	}
+	defer p.Close()
+
+	extra, err := factory()
+	if err != nil {
+		t.Fatalf("factory error: %s", err)
+	}
+
+	// pool is already full (InitialCap == MaxCap), so this Put is discarded
+	if err := p.Put(extra); err == nil {
+		t.Errorf("expected Put into a full pool to return an error")
+	}
+
+	stats := p.Stats()
+	if stats.Discards != 1 {
+		t.Errorf("expected 1 Discard, got %d", stats.Discards)
+	}
+	if stats.Closes != 1 {
+		t.Errorf("expected 1 Close, got %d", stats.Closes)
+	}
+}
+
+// TestChannelPool_StatsCurrentInUse_IgnoresExternalConns guards against
+// CurrentInUse under-reporting: Put-ing and discarding externally-dialed
+// connections (never obtained via Get/GetContext) must not be mistaken for
+// checked-out pool capacity.
+func TestChannelPool_StatsCurrentInUse_IgnoresExternalConns(t *testing.T) {
+	p, err := NewChannelPoolConfig(Config{
+		InitialCap: 0,
+		MaxCap:     1,
+		Factory:    factory,
+	})
+	if err != nil {
+		t.Fatalf("NewChannelPoolConfig error: %s", err)
+	}
+	defer p.Close()
+
+	conn, err := p.Get()
+	if err != nil {
+		t.Fatalf("Get error: %s", err)
+	}
+
+	if stats := p.Stats(); stats.CurrentInUse != 1 {
+		t.Errorf("expected CurrentInUse == 1 after Get, got %d", stats.CurrentInUse)
+	}
+
+	// fill the now-empty idle channel (cap 1) with an externally-dialed
+	// conn, so the next external Put below finds the pool full and discards.
+	filler, err := factory()
+	if err != nil {
+		t.Fatalf("factory error: %s", err)
+	}
+	if err := p.Put(filler); err != nil {
+		t.Fatalf("Put error: %s", err)
+	}
+
+	extra, err := factory()
+	if err != nil {
+		t.Fatalf("factory error: %s", err)
+	}
+	// pool is full (filler occupies the only idle slot), so this external
+	// conn is discarded
+	if err := p.Put(extra); err == nil {
+		t.Errorf("expected Put into a full pool to return an error")
+	}
+
+	if stats := p.Stats(); stats.CurrentInUse != 1 {
+		t.Errorf("expected CurrentInUse to still be 1 after discarding an external conn, got %d", stats.CurrentInUse)
+	}
+
+	// drain filler so conn (still checked out, slotted) can go back idle
+	if _, err := p.Get(); err != nil {
+		t.Fatalf("Get error: %s", err)
+	}
+
+	if err := p.Put(conn); err != nil {
+		t.Fatalf("Put error: %s", err)
+	}
+	if stats := p.Stats(); stats.CurrentInUse != 0 {
+		t.Errorf("expected CurrentInUse == 0 after Put, got %d", stats.CurrentInUse)
+	}
+}
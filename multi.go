@@ -0,0 +1,170 @@
+package pool
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// AddrFactory is a function that dials a new connection to the given
+// address.
+type AddrFactory func(addr string) (net.Conn, error)
+
+// MultiConnPool maintains a bounded Pool per destination address, so a
+// single client can keep pooled connections to many remote peers - the
+// pattern used by cluster clients doing internode RPC where the dialer
+// target varies per call.
+type MultiConnPool struct {
+	mu sync.RWMutex
+
+	pools map[string]*hostPool
+
+	initialCap int
+	maxCap     int
+	factory    AddrFactory
+	idleTTL    time.Duration
+
+	done   chan struct{}
+	closed bool
+}
+
+// hostPool is a single address's sub-pool plus the bookkeeping needed to
+// reap it once it has been idle for longer than idleTTL.
+type hostPool struct {
+	pool     Pool
+	lastUsed time.Time
+}
+
+// NewMultiConnPool returns a MultiConnPool that lazily creates a sub-pool
+// for an address the first time GetTo is called for it, using initialCap
+// and maxCap for every sub-pool. If idleTTL is greater than zero, a
+// background goroutine periodically closes and removes sub-pools that have
+// not been used for at least idleTTL, so memory doesn't grow unbounded with
+// transient peers. A zero idleTTL disables reaping.
+func NewMultiConnPool(initialCap, maxCap int, factory AddrFactory, idleTTL time.Duration) *MultiConnPool {
+	m := &MultiConnPool{
+		pools:      make(map[string]*hostPool),
+		initialCap: initialCap,
+		maxCap:     maxCap,
+		factory:    factory,
+		idleTTL:    idleTTL,
+		done:       make(chan struct{}),
+	}
+
+	if idleTTL > 0 {
+		go m.reapLoop()
+	}
+
+	return m
+}
+
+func (m *MultiConnPool) getOrCreate(addr string) (Pool, error) {
+	m.mu.RLock()
+	hp, ok := m.pools[addr]
+	m.mu.RUnlock()
+	if ok {
+		m.mu.Lock()
+		hp.lastUsed = time.Now()
+		m.mu.Unlock()
+		return hp.pool, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, errors.New("pool is closed")
+	}
+
+	// another goroutine may have created the sub-pool while we waited for
+	// the write lock
+	if hp, ok := m.pools[addr]; ok {
+		hp.lastUsed = time.Now()
+		return hp.pool, nil
+	}
+
+	p, err := NewChannelPool(m.initialCap, m.maxCap, func() (net.Conn, error) {
+		return m.factory(addr)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.pools[addr] = &hostPool{pool: p, lastUsed: time.Now()}
+	return p, nil
+}
+
+// GetTo returns a connection to addr, lazily creating addr's sub-pool on
+// first use.
+func (m *MultiConnPool) GetTo(addr string) (net.Conn, error) {
+	p, err := m.getOrCreate(addr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Get()
+}
+
+// PutTo returns conn to addr's sub-pool. If addr has no sub-pool, e.g.
+// because it was reaped while conn was checked out, conn is simply closed.
+func (m *MultiConnPool) PutTo(addr string, conn net.Conn) error {
+	m.mu.RLock()
+	hp, ok := m.pools[addr]
+	m.mu.RUnlock()
+	if !ok {
+		return conn.Close()
+	}
+	return hp.pool.Put(conn)
+}
+
+// CloseAll closes every sub-pool and stops the idle reaper. After
+// CloseAll() the MultiConnPool is no longer usable.
+func (m *MultiConnPool) CloseAll() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	pools := m.pools
+	m.pools = make(map[string]*hostPool)
+	m.mu.Unlock()
+
+	close(m.done)
+
+	for _, hp := range pools {
+		hp.pool.Close()
+	}
+}
+
+func (m *MultiConnPool) reapLoop() {
+	ticker := time.NewTicker(m.idleTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+			m.reapIdle()
+		}
+	}
+}
+
+func (m *MultiConnPool) reapIdle() {
+	cutoff := time.Now().Add(-m.idleTTL)
+
+	m.mu.Lock()
+	var stale []*hostPool
+	for addr, hp := range m.pools {
+		if hp.lastUsed.Before(cutoff) {
+			stale = append(stale, hp)
+			delete(m.pools, addr)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, hp := range stale {
+		hp.pool.Close()
+	}
+}
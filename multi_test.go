@@ -0,0 +1,85 @@
+package pool
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func newMultiPool(idleTTL time.Duration) *MultiConnPool {
+	return NewMultiConnPool(InitialCap, MaximumCap, func(addr string) (net.Conn, error) {
+		return factory()
+	}, idleTTL)
+}
+
+func TestMultiConnPool_GetPutTo(t *testing.T) {
+	m := newMultiPool(0)
+	defer m.CloseAll()
+
+	conn, err := m.GetTo(address)
+	if err != nil {
+		t.Errorf("GetTo error: %s", err)
+	}
+
+	if err := m.PutTo(address, conn); err != nil {
+		t.Errorf("PutTo error: %s", err)
+	}
+}
+
+func TestMultiConnPool_LazyCreation(t *testing.T) {
+	m := newMultiPool(0)
+	defer m.CloseAll()
+
+	m.mu.RLock()
+	n := len(m.pools)
+	m.mu.RUnlock()
+	if n != 0 {
+		t.Errorf("expected no sub-pools before first GetTo, got %d", n)
+	}
+
+	if _, err := m.GetTo(address); err != nil {
+		t.Errorf("GetTo error: %s", err)
+	}
+
+	m.mu.RLock()
+	n = len(m.pools)
+	m.mu.RUnlock()
+	if n != 1 {
+		t.Errorf("expected 1 sub-pool after GetTo, got %d", n)
+	}
+}
+
+func TestMultiConnPool_Reap(t *testing.T) {
+	m := newMultiPool(50 * time.Millisecond)
+	defer m.CloseAll()
+
+	if _, err := m.GetTo(address); err != nil {
+		t.Errorf("GetTo error: %s", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	m.mu.RLock()
+	n := len(m.pools)
+	m.mu.RUnlock()
+	if n != 0 {
+		t.Errorf("expected idle sub-pool to be reaped, got %d remaining", n)
+	}
+}
+
+func TestMultiConnPool_CloseAll(t *testing.T) {
+	m := newMultiPool(0)
+
+	if _, err := m.GetTo(address); err != nil {
+		t.Errorf("GetTo error: %s", err)
+	}
+
+	m.CloseAll()
+
+	m.mu.RLock()
+	n := len(m.pools)
+	m.mu.RUnlock()
+	if n != 0 {
+		t.Errorf("expected no sub-pools after CloseAll, got %d", n)
+	}
+}
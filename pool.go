@@ -2,133 +2,46 @@
 package pool
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"net"
-	"sync"
+	"time"
 )
 
-// Factory is a function to create new connections.
-type Factory func() (net.Conn, error)
+// Pool interface describes a pool implementation. A pool should have maximum
+// capacity. An ideal pool is threadsafe and easy to use.
+type Pool interface {
+	// Get returns a new connection from the pool. Closing the connection
+	// puts it back into the Pool, unless the connection was marked unusable
+	// via PoolConn.MarkUnusable(), in which case it is destroyed instead.
+	Get() (net.Conn, error)
 
-// Factory is a function to create new connections.
-type Pool struct {
-	// storage for our net.Conn connections
-	conns chan net.Conn
+	// GetContext is like Get, but it blocks until a connection becomes
+	// available - either idle in the pool or under the pool's maxCap - or
+	// until ctx is done, in which case ctx.Err() is returned. Like Get, it
+	// never lets the number of live connections exceed maxCap.
+	GetContext(ctx context.Context) (net.Conn, error)
 
-	// net.Conn generator
-	factory Factory
+	// Put puts the given connection back into the pool. If the pool is full,
+	// conn is closed and an error is returned. If the pool is closed, conn is
+	// closed and the error from closing it, if any, is returned.
+	Put(conn net.Conn) error
 
-	mu sync.Mutex // protects isDesroyed field
-}
-
-// New returns a new pool with an initial capacity and maximum capacity.
-// Factory is used when initial capacity is greater then zero to fill the  pool.
-func New(initalCap, maxCap int, factory Factory) (*Pool, error) {
-	if initalCap <= 0 || maxCap <= 0 || initalCap > maxCap {
-		return nil, errors.New("invalid capacity settings")
-	}
-
-	p := &Pool{
-		conns: make(chan net.Conn, maxCap),
-		factory: factory,
-	}
+	// Close closes the pool and all its connections. After Close() the pool
+	// is no longer usable.
+	Close()
 
-	// create initial connections, if something goes wrong,
-	// just close the pool error out.
-	for i := 0; i < initalCap; i++ {
-		conn, err := factory()
-		if err != nil {
-			p.Close()
-			return nil, fmt.Errorf("factory is not able to fill the pool: %s", err)
-		}
-		p.conns <- conn
-	}
-
-	return p, nil
-}
+	// CloseWithTimeout stops the pool from handing out new connections,
+	// waits up to d for connections currently checked out to be returned via
+	// Put, and then closes the pool - force-closing any connection that is
+	// still outstanding once d elapses.
+	CloseWithTimeout(d time.Duration)
 
-func (p *Pool) getConns() chan net.Conn {
-	p.mu.Lock()
-	conns := p.conns
-	p.mu.Unlock()
-	return conns
-}
-
-// Get returns a new connection from the pool. After using the connection it
-// should be put back via the Put() method. If there is no new connection
-// available in the pool, a new connection will be created via the Factory()
-// method.
-func (p *Pool) Get() (net.Conn, error) {
-	conns := p.getConns()
-	if conns == nil {
-		return nil, errors.New("pool is closed")
-	}
-
-	select {
-	case conn := <- p.conns:
-		if conn == nil {
-			return nil, errors.New("pool is closed")
-		}
-		return conn, nil
-	default:
-		return p.factory()
-	}
-}
+	// Len returns the current number of idle connections in the pool.
+	Len() int
 
-// Put puts an existing connection into the pool. If the pool is full or closed, conn is
-// simply closed.
-func (p *Pool) Put(conn net.Conn) {
-	if conn == nil {
-		return
-	}
-	if !p.put(conn) {
-		_ = conn.Close()
-	}
-}
-
-func (p *Pool) put(conn net.Conn) bool {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-	if p.conns == nil {
-		return false
-	}
-
-	select {
-	case p.conns <- conn:
-		return true
-	default:
-	}
-	return false
-}
-
-// Close closes the pool and all its connections. After Close() the
-// pool is no longer usable.
-func (p *Pool) Close() {
-	conns := p.closePool()
-	if conns == nil {
-		return
-	}
-	close(conns)
-	for conn := range conns {
-		_ = conn.Close()
-	}
-}
-
-func (p *Pool) closePool() chan net.Conn {
-	p.mu.Lock()
-	defer p.mu.Unlock()
-	conns := p.conns
-	p.conns = nil
-	p.factory = nil
-	return conns
-}
+	// Cap returns the maximum capacity of the pool.
+	Cap() int
 
-// MaximumCapacity returns the maximum capacity of the pool
-func (p *Pool) MaximumCapacity() int {
-	return cap(p.conns)
+	// Stats returns a snapshot of the pool's lifetime counters.
+	Stats() Stats
 }
-// UsedCapacity returns the used capacity of the pool.
-func (p *Pool) UsedCapacity() int {
-	return len(p.conns)
-}
\ No newline at end of file